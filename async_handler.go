@@ -0,0 +1,176 @@
+package logging
+
+import "sync"
+
+// OverflowPolicy controls what an AsyncHandler does when its buffer is full.
+type OverflowPolicy int
+
+const (
+	// Block waits for room in the buffer, applying backpressure to the caller.
+	Block OverflowPolicy = iota
+
+	// DropOldest discards the oldest buffered record to make room for the
+	// incoming one.
+	DropOldest
+
+	// DropNewest discards the incoming record when the buffer is full.
+	DropNewest
+)
+
+// DefaultAsyncCapacity is the buffer size used when NewAsyncHandler is
+// given a non-positive capacity.
+const DefaultAsyncCapacity = 500
+
+/////////////////
+//             //
+// AsyncHandler //
+//             //
+/////////////////
+
+// AsyncHandler wraps a Handler and hands records off to a background
+// goroutine through a buffered channel, so Handle does not wait for a slow
+// sink while the buffer has room. Once the buffer is full, the
+// OverflowPolicy decides: Block waits for room, the Drop policies return
+// at once. Close flushes any pending records and stops the goroutine.
+type AsyncHandler struct {
+	handler Handler
+	policy  OverflowPolicy
+	records chan *Record
+	flush   chan chan struct{}
+	done    chan struct{}
+	closer  sync.Once
+
+	// sendMu guards every send on records against a concurrent Close:
+	// Handle holds the read lock for the duration of its send, and Close
+	// takes the write lock (which waits for those sends to finish) before
+	// closing the channel, so Handle can never race a close(h.records).
+	sendMu sync.RWMutex
+	closed bool
+}
+
+// NewAsyncHandler wraps handler with a buffered channel of the given
+// capacity (DefaultAsyncCapacity if capacity <= 0) and overflow policy, and
+// starts the draining goroutine.
+func NewAsyncHandler(handler Handler, capacity int, policy OverflowPolicy) *AsyncHandler {
+	if capacity <= 0 {
+		capacity = DefaultAsyncCapacity
+	}
+	h := &AsyncHandler{
+		handler: handler,
+		policy:  policy,
+		records: make(chan *Record, capacity),
+		flush:   make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go h.loop()
+	return h
+}
+
+func (h *AsyncHandler) loop() {
+	defer close(h.done)
+	for {
+		// Give a pending flush priority over draining more records: under
+		// sustained load the records case below is always ready too, and
+		// select's random pick between two always-ready cases can starve
+		// Flush/Close for as long as producers keep the buffer full.
+		select {
+		case ack := <-h.flush:
+			h.drain()
+			close(ack)
+		default:
+		}
+
+		select {
+		case rec, ok := <-h.records:
+			if !ok {
+				return
+			}
+			h.handler.Handle(rec)
+		case ack := <-h.flush:
+			h.drain()
+			close(ack)
+		}
+	}
+}
+
+// drain handles the records already sitting in the buffer when it is
+// called, without blocking on new ones arriving. Records that producers
+// add while it runs are left for the main loop, so a steady stream of
+// sends cannot keep drain from returning.
+func (h *AsyncHandler) drain() {
+	for n := len(h.records); n > 0; n-- {
+		select {
+		case rec, ok := <-h.records:
+			if !ok {
+				return
+			}
+			h.handler.Handle(rec)
+		default:
+			return
+		}
+	}
+}
+
+func (h *AsyncHandler) SetFormatter(f Formatter) { h.handler.SetFormatter(f) }
+
+func (h *AsyncHandler) SetLevel(l Level) { h.handler.SetLevel(l) }
+
+func (h *AsyncHandler) Handle(rec *Record) {
+	h.sendMu.RLock()
+	defer h.sendMu.RUnlock()
+	if h.closed {
+		return
+	}
+
+	select {
+	case h.records <- rec:
+		return
+	default:
+	}
+
+	switch h.policy {
+	case DropNewest:
+		// Buffer is full; drop the incoming record.
+	case DropOldest:
+		select {
+		case <-h.records:
+		default:
+		}
+		select {
+		case h.records <- rec:
+		default:
+		}
+	default: // Block
+		h.records <- rec
+	}
+}
+
+// Flush blocks until every record currently buffered has been passed to
+// the wrapped Handler.
+func (h *AsyncHandler) Flush() {
+	ack := make(chan struct{})
+	select {
+	case h.flush <- ack:
+		<-ack
+	case <-h.done:
+	}
+	h.handler.Flush()
+}
+
+// Close flushes pending records, stops the draining goroutine and closes
+// the wrapped Handler. It is safe to call concurrently with Handle: no
+// further record is accepted once Close starts, and none is ever sent on
+// the now-closed records channel.
+func (h *AsyncHandler) Close() {
+	h.closer.Do(func() {
+		h.Flush()
+
+		h.sendMu.Lock()
+		h.closed = true
+		close(h.records)
+		h.sendMu.Unlock()
+
+		<-h.done
+		h.handler.Close()
+	})
+}
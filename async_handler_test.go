@@ -0,0 +1,134 @@
+package logging
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingHandler is a test Handler that records how many times Handle
+// was called, sleeping briefly to simulate a slow sink.
+type countingHandler struct {
+	*BaseHandler
+	delay time.Duration
+	mu    sync.Mutex
+	count int
+}
+
+func newCountingHandler(delay time.Duration) *countingHandler {
+	return &countingHandler{BaseHandler: NewBaseHandler(), delay: delay}
+}
+
+func (h *countingHandler) Handle(rec *Record) {
+	if h.delay > 0 {
+		time.Sleep(h.delay)
+	}
+	h.mu.Lock()
+	h.count++
+	h.mu.Unlock()
+}
+
+func (h *countingHandler) Close() {}
+
+func (h *countingHandler) Counted() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+func TestAsyncHandlerDropNewestDropsUnderOverflow(t *testing.T) {
+	sink := newCountingHandler(50 * time.Millisecond)
+	h := NewAsyncHandler(sink, 1, DropNewest)
+	defer h.Close()
+
+	for i := 0; i < 10; i++ {
+		h.Handle(&Record{})
+	}
+	h.Flush()
+
+	if got := sink.Counted(); got >= 10 {
+		t.Fatalf("expected DropNewest to shed records under overflow, got %d handled out of 10", got)
+	}
+}
+
+func TestAsyncHandlerFlushDoesNotStarveUnderLoad(t *testing.T) {
+	sink := newCountingHandler(5 * time.Millisecond)
+	h := NewAsyncHandler(sink, 500, Block)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					h.Handle(&Record{})
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		h.Flush()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	// A full buffer of 500 records at 5ms each takes about 2.5s to drain.
+	case <-time.After(10 * time.Second):
+		t.Fatal("Flush did not return within 10s under sustained concurrent load")
+	}
+
+	close(stop)
+	wg.Wait()
+	h.Close()
+}
+
+func TestAsyncHandlerCloseDoesNotRaceConcurrentHandle(t *testing.T) {
+	sink := newCountingHandler(time.Millisecond)
+	h := NewAsyncHandler(sink, 8, Block)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					h.Handle(&Record{})
+				}
+			}
+		}()
+	}
+
+	// Start Close while producers are still actively sending: this must
+	// never panic with "send on closed channel". Stop the producers
+	// shortly after so Close (and the Flush it performs first) can make
+	// forward progress instead of racing a continuous stream forever.
+	time.Sleep(5 * time.Millisecond)
+	closeDone := make(chan struct{})
+	go func() {
+		h.Close()
+		close(closeDone)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+
+	select {
+	case <-closeDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close did not return after producers stopped")
+	}
+}
@@ -0,0 +1,27 @@
+package logging
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// shouldColorize reports whether w should receive ANSI color escapes. It
+// is disabled globally by the NO_COLOR environment variable
+// (https://no-color.org), and otherwise only enabled for an *os.File
+// pointing at an interactive terminal; on Windows it additionally requires
+// that console support virtual terminal sequences (see
+// enableVirtualTerminal in color_windows.go).
+func shouldColorize(w io.Writer) bool {
+	if _, noColor := os.LookupEnv("NO_COLOR"); noColor {
+		return false
+	}
+
+	f, ok := w.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return false
+	}
+
+	return enableVirtualTerminal(f)
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package logging
+
+import "os"
+
+// enableVirtualTerminal is a no-op outside Windows: every other supported
+// terminal already understands ANSI color escapes natively.
+func enableVirtualTerminal(f *os.File) bool {
+	return true
+}
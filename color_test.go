@@ -0,0 +1,20 @@
+package logging
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestShouldColorizeHonorsNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if shouldColorize(os.Stderr) {
+		t.Fatal("expected shouldColorize to be false when NO_COLOR is set")
+	}
+}
+
+func TestShouldColorizeFalseForNonFileWriter(t *testing.T) {
+	if shouldColorize(&bytes.Buffer{}) {
+		t.Fatal("expected shouldColorize to be false for a non-*os.File writer")
+	}
+}
@@ -0,0 +1,35 @@
+//go:build windows
+
+package logging
+
+import (
+	"os"
+	"syscall"
+)
+
+const enableVirtualTerminalProcessing = 0x0004
+
+var (
+	kernel32           = syscall.NewLazyDLL("kernel32.dll")
+	procSetConsoleMode = kernel32.NewProc("SetConsoleMode")
+)
+
+// enableVirtualTerminal turns on ENABLE_VIRTUAL_TERMINAL_PROCESSING for
+// f's console. It reports whether colorized output is safe to use,
+// falling back to plain (uncolored) output when the console mode cannot
+// be changed, e.g. legacy cmd.exe on older Windows releases.
+//
+// syscall.SetConsoleMode isn't exposed by the stdlib syscall package (only
+// GetConsoleMode is), so SetConsoleMode is called directly through
+// kernel32.dll instead of pulling in golang.org/x/sys/windows for it.
+func enableVirtualTerminal(f *os.File) bool {
+	handle := syscall.Handle(f.Fd())
+
+	var mode uint32
+	if err := syscall.GetConsoleMode(handle, &mode); err != nil {
+		return false
+	}
+
+	r, _, _ := procSetConsoleMode.Call(uintptr(handle), uintptr(mode|enableVirtualTerminalProcessing))
+	return r != 0
+}
@@ -0,0 +1,245 @@
+package logging
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// RotationInterval selects the time-based rotation granularity for a
+// FileHandler.
+type RotationInterval int
+
+const (
+	// NoInterval disables time-based rotation; only MaxBytes (if set)
+	// triggers rotation.
+	NoInterval RotationInterval = iota
+
+	// Hourly rotates the file once an hour has passed since it was opened.
+	Hourly
+
+	// Daily rotates the file once a day has passed since it was opened.
+	Daily
+)
+
+/////////////////
+//             //
+// FileHandler //
+//             //
+/////////////////
+
+// FileHandler is a Handler that writes to a file path rather than an
+// arbitrary io.Writer, and knows how to rotate that file: by size
+// (MaxBytes), by time (Interval), keeping MaxBackups rotated copies
+// (optionally gzip-compressed via Compress). Reopen closes and reopens
+// the file, and is wired to SIGHUP so external tools like logrotate can
+// rotate the file out from under a long-running process.
+type FileHandler struct {
+	*BaseHandler
+
+	// MaxBytes rotates the file once writing would exceed this size. Zero
+	// disables size-based rotation.
+	MaxBytes int64
+
+	// Interval selects time-based rotation. NoInterval disables it.
+	Interval RotationInterval
+
+	// MaxBackups is the number of rotated files to keep; older ones are
+	// removed. Zero keeps every backup.
+	MaxBackups int
+
+	// Compress gzip-compresses rotated backups in the background.
+	Compress bool
+
+	mu       sync.Mutex
+	path     string
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	sighup   chan os.Signal
+}
+
+// NewFileHandler opens (creating if necessary) the file at path for
+// appending and returns a FileHandler writing to it.
+func NewFileHandler(path string) (*FileHandler, error) {
+	h := &FileHandler{
+		BaseHandler: NewBaseHandler(),
+		path:        path,
+	}
+	if err := h.open(); err != nil {
+		return nil, err
+	}
+	h.watchSighup()
+	return h, nil
+}
+
+func (h *FileHandler) open() error {
+	if dir := filepath.Dir(h.path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	h.file = f
+	h.size = info.Size()
+	h.openedAt = time.Now()
+	return nil
+}
+
+// watchSighup wires Reopen to SIGHUP so external log rotation (e.g.
+// logrotate) can tell a long-running process to reopen its log file.
+func (h *FileHandler) watchSighup() {
+	h.sighup = make(chan os.Signal, 1)
+	signal.Notify(h.sighup, syscall.SIGHUP)
+	go func() {
+		for range h.sighup {
+			h.Reopen()
+		}
+	}()
+}
+
+// Reopen closes and reopens the underlying file, picking up a new inode
+// if the path was moved aside by external rotation.
+func (h *FileHandler) Reopen() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.file != nil {
+		h.file.Close()
+	}
+	return h.open()
+}
+
+func (h *FileHandler) Handle(rec *Record) {
+	message := h.BaseHandler.FilterAndFormat(rec)
+	if message == "" {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.shouldRotateLocked(int64(len(message))) {
+		if err := h.rotateLocked(); err != nil {
+			fmt.Fprintf(os.Stderr, "logging: failed to rotate %s: %s\n", h.path, err)
+		}
+	}
+
+	n, err := io.WriteString(h.file, message)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "logging: failed to write to %s: %s\n", h.path, err)
+		return
+	}
+	h.size += int64(n)
+}
+
+func (h *FileHandler) shouldRotateLocked(next int64) bool {
+	if h.MaxBytes > 0 && h.size+next > h.MaxBytes {
+		return true
+	}
+	switch h.Interval {
+	case Hourly:
+		return time.Since(h.openedAt) >= time.Hour
+	case Daily:
+		return time.Since(h.openedAt) >= 24*time.Hour
+	}
+	return false
+}
+
+// rotateLocked renames the current file aside with a timestamp suffix,
+// optionally gzips it, reopens path fresh, and prunes old backups. Caller
+// must hold h.mu.
+func (h *FileHandler) rotateLocked() error {
+	if h.file != nil {
+		h.file.Close()
+	}
+
+	backup := h.path + "." + time.Now().Format("20060102-150405")
+	if err := os.Rename(h.path, backup); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if h.Compress {
+		go h.compressBackup(backup)
+	}
+
+	if err := h.open(); err != nil {
+		return err
+	}
+	h.pruneBackups()
+	return nil
+}
+
+func (h *FileHandler) compressBackup(backup string) {
+	in, err := os.Open(backup)
+	if err != nil {
+		return
+	}
+	defer in.Close()
+
+	out, err := os.Create(backup + ".gz")
+	if err != nil {
+		return
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		gz.Close()
+		return
+	}
+	if err := gz.Close(); err != nil {
+		return
+	}
+	os.Remove(backup)
+}
+
+func (h *FileHandler) pruneBackups() {
+	if h.MaxBackups <= 0 {
+		return
+	}
+	matches, err := filepath.Glob(h.path + ".*")
+	if err != nil || len(matches) <= h.MaxBackups {
+		return
+	}
+	sort.Strings(matches)
+	for _, old := range matches[:len(matches)-h.MaxBackups] {
+		os.Remove(old)
+	}
+}
+
+func (h *FileHandler) Flush() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.file != nil {
+		h.file.Sync()
+	}
+}
+
+func (h *FileHandler) Close() {
+	// signal.Stop guarantees h.sighup receives no further signals before
+	// it returns, so closing it right after is safe and lets the
+	// watchSighup goroutine's "for range h.sighup" loop exit instead of
+	// blocking forever.
+	signal.Stop(h.sighup)
+	close(h.sighup)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.file != nil {
+		h.file.Close()
+	}
+}
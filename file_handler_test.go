@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileHandlerRotatesBySizeAndPrunesBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	h, err := NewFileHandler(path)
+	if err != nil {
+		t.Fatalf("NewFileHandler: %s", err)
+	}
+	defer h.Close()
+
+	h.MaxBytes = 10
+	h.MaxBackups = 2
+
+	for i := 0; i < 20; i++ {
+		h.Handle(&Record{Format: "0123456789\n"})
+	}
+
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatalf("Glob: %s", err)
+	}
+	if len(matches) == 0 || len(matches) > h.MaxBackups {
+		t.Fatalf("expected between 1 and %d pruned backups, got %d: %v", h.MaxBackups, len(matches), matches)
+	}
+}
+
+func TestFileHandlerCloseStopsSighupWatcher(t *testing.T) {
+	dir := t.TempDir()
+
+	// The first signal.Notify in the process starts os/signal's own
+	// watcher goroutine, which never exits; open and close one handler
+	// before counting so that goroutine is not mistaken for a leak.
+	warm, err := NewFileHandler(filepath.Join(dir, "warm.log"))
+	if err != nil {
+		t.Fatalf("NewFileHandler: %s", err)
+	}
+	warm.Close()
+	time.Sleep(10 * time.Millisecond)
+
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 5; i++ {
+		h, err := NewFileHandler(filepath.Join(dir, "app.log"))
+		if err != nil {
+			t.Fatalf("NewFileHandler: %s", err)
+		}
+		h.Close()
+	}
+
+	// Give the watcher goroutines a moment to actually exit after Close.
+	var after int
+	for i := 0; i < 20; i++ {
+		time.Sleep(10 * time.Millisecond)
+		after = runtime.NumGoroutine()
+		if after <= before {
+			break
+		}
+	}
+
+	if after > before {
+		t.Fatalf("goroutine count grew from %d to %d after closing 5 FileHandlers; sighup watchers likely leaked", before, after)
+	}
+}
+
+func TestFileHandlerFormatIsWritten(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	h, err := NewFileHandler(path)
+	if err != nil {
+		t.Fatalf("NewFileHandler: %s", err)
+	}
+	defer h.Close()
+
+	h.Handle(&Record{Format: "hello\n", Level: INFO})
+	h.Flush()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading log file: %s", err)
+	}
+	if !strings.Contains(string(data), "hello") {
+		t.Fatalf("expected log file to contain %q, got %q", "hello", data)
+	}
+}
@@ -0,0 +1,151 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+////////////////////
+//                //
+// JSONFormatter  //
+//                //
+////////////////////
+
+// JSONFormatter formats a Record as a single line JSON object, suitable for
+// machine parsing by log aggregators. The "time" field is RFC3339 and any
+// Fields attached via Logger.WithField/WithFields are merged into the
+// top-level object.
+type JSONFormatter struct{}
+
+// jsonReservedKeys are the top-level keys JSONFormatter.Format always sets
+// itself; a Field with one of these names is renamed to "fields.<name>" so
+// it can't clobber them.
+var jsonReservedKeys = map[string]struct{}{
+	"time":    {},
+	"level":   {},
+	"logger":  {},
+	"file":    {},
+	"line":    {},
+	"pid":     {},
+	"message": {},
+	"stack":   {},
+}
+
+func (f *JSONFormatter) Format(rec *Record) string {
+	entry := make(map[string]interface{}, len(rec.Fields)+7)
+	entry["time"] = rec.Time.Format(time.RFC3339)
+	entry["level"] = LevelNames[rec.Level]
+	entry["logger"] = rec.LoggerName
+	entry["file"] = rec.Filename
+	entry["line"] = rec.Line
+	entry["pid"] = rec.ProcessID
+	entry["message"] = recordMessage(rec)
+	if len(rec.Stack) > 0 {
+		stack := make([]map[string]interface{}, len(rec.Stack))
+		for i, frame := range rec.Stack {
+			stack[i] = map[string]interface{}{
+				"func": frame.Function,
+				"file": frame.File,
+				"line": frame.Line,
+			}
+		}
+		entry["stack"] = stack
+	}
+	for k, v := range rec.Fields {
+		if _, reserved := jsonReservedKeys[k]; reserved {
+			k = "fields." + k
+		}
+		entry[k] = v
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Sprintf(`{"level":"ERROR","message":"logging: failed to marshal record: %s"}`+"\n", err)
+	}
+	return string(b) + "\n"
+}
+
+////////////////////////
+//                    //
+// LogfmtFormatter    //
+//                    //
+////////////////////////
+
+// LogfmtFormatter formats a Record as logfmt "key=value" pairs
+// (time, level, logger, file, line, pid, msg, then any Fields in sorted
+// key order), quoting values that contain spaces or quotes.
+type LogfmtFormatter struct{}
+
+// logfmtReservedKeys are the key names LogfmtFormatter.Format always writes
+// itself; a Field with one of these names is renamed to "fields.<name>" so
+// it can't produce a duplicate key in the output.
+var logfmtReservedKeys = map[string]struct{}{
+	"time":   {},
+	"level":  {},
+	"logger": {},
+	"file":   {},
+	"line":   {},
+	"pid":    {},
+	"msg":    {},
+}
+
+func (f *LogfmtFormatter) Format(rec *Record) string {
+	var b strings.Builder
+	writeLogfmtPair(&b, "time", rec.Time.Format(time.RFC3339))
+	writeLogfmtPair(&b, "level", LevelNames[rec.Level])
+	writeLogfmtPair(&b, "logger", rec.LoggerName)
+	writeLogfmtPair(&b, "file", rec.Filename)
+	writeLogfmtPair(&b, "line", strconv.Itoa(rec.Line))
+	writeLogfmtPair(&b, "pid", strconv.Itoa(rec.ProcessID))
+	writeLogfmtPair(&b, "msg", recordMessage(rec))
+
+	keys := make([]string, 0, len(rec.Fields))
+	for k := range rec.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		name := k
+		if _, reserved := logfmtReservedKeys[name]; reserved {
+			name = "fields." + name
+		}
+		writeLogfmtPair(&b, name, fmt.Sprint(rec.Fields[k]))
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
+
+func writeLogfmtPair(b *strings.Builder, key, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(key)
+	b.WriteByte('=')
+	if needsLogfmtQuoting(value) {
+		b.WriteString(strconv.Quote(value))
+	} else {
+		b.WriteString(value)
+	}
+}
+
+func needsLogfmtQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '"' || r == '=' {
+			return true
+		}
+	}
+	return false
+}
+
+// recordMessage returns the formatted message without the trailing newline
+// that logger.log appends to every Format string.
+func recordMessage(rec *Record) string {
+	return strings.TrimSuffix(fmt.Sprintf(rec.Format, rec.Args...), "\n")
+}
@@ -0,0 +1,106 @@
+package logging
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONFormatterMergesFieldsAndMessage(t *testing.T) {
+	rec := &Record{
+		Format:     "hello %s\n",
+		Args:       []interface{}{"world"},
+		LoggerName: "app",
+		Level:      INFO,
+		Time:       time.Unix(0, 0).UTC(),
+		Fields:     Fields{"request_id": "abc"},
+	}
+
+	f := &JSONFormatter{}
+	out := f.Format(rec)
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %s\noutput: %q", err, out)
+	}
+	if decoded["message"] != "hello world" {
+		t.Errorf("message = %v, want %q", decoded["message"], "hello world")
+	}
+	if decoded["level"] != "INFO" {
+		t.Errorf("level = %v, want INFO", decoded["level"])
+	}
+	if decoded["request_id"] != "abc" {
+		t.Errorf("request_id = %v, want abc", decoded["request_id"])
+	}
+}
+
+func TestJSONFormatterFieldsCannotClobberReservedKeys(t *testing.T) {
+	rec := &Record{
+		Format:     "hello\n",
+		LoggerName: "app",
+		Level:      INFO,
+		Time:       time.Unix(0, 0).UTC(),
+		Fields:     Fields{"level": "bogus", "message": "bogus"},
+	}
+
+	f := &JSONFormatter{}
+	out := f.Format(rec)
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %s\noutput: %q", err, out)
+	}
+	if decoded["level"] != "INFO" {
+		t.Errorf("level = %v, want INFO (a level field must not clobber it)", decoded["level"])
+	}
+	if decoded["message"] != "hello" {
+		t.Errorf("message = %v, want %q (a message field must not clobber it)", decoded["message"], "hello")
+	}
+	if decoded["fields.level"] != "bogus" || decoded["fields.message"] != "bogus" {
+		t.Errorf("expected colliding fields under fields.level/fields.message, got %v", decoded)
+	}
+}
+
+func TestLogfmtFormatterQuotesValuesWithSpaces(t *testing.T) {
+	rec := &Record{
+		Format:     "hello world\n",
+		LoggerName: "app",
+		Level:      INFO,
+		Time:       time.Unix(0, 0).UTC(),
+		Fields:     Fields{"path": "/a b"},
+	}
+
+	f := &LogfmtFormatter{}
+	out := f.Format(rec)
+
+	if !strings.Contains(out, `msg="hello world"`) {
+		t.Errorf("expected quoted msg with a space, got %q", out)
+	}
+	if !strings.Contains(out, `path="/a b"`) {
+		t.Errorf("expected quoted field with a space, got %q", out)
+	}
+}
+
+func TestLogfmtFormatterFieldsCannotDuplicateReservedKeys(t *testing.T) {
+	rec := &Record{
+		Format:     "hello\n",
+		LoggerName: "app",
+		Level:      INFO,
+		Time:       time.Unix(0, 0).UTC(),
+		Fields:     Fields{"msg": "bogus"},
+	}
+
+	f := &LogfmtFormatter{}
+	out := f.Format(rec)
+
+	if !strings.Contains(out, "msg=hello") {
+		t.Errorf("expected the real msg pair untouched, got %q", out)
+	}
+	if !strings.Contains(out, "fields.msg=bogus") {
+		t.Errorf("expected colliding field under fields.msg, got %q", out)
+	}
+	if strings.Count(out, "msg=") != 2 {
+		t.Errorf("expected exactly one real msg= and one fields.msg=, got %q", out)
+	}
+}
@@ -3,12 +3,10 @@ package logging
 import (
 	"fmt"
 	"io"
-	"log/syslog"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
-	"sync"
 	"time"
 )
 
@@ -58,19 +56,15 @@ var LevelColors = map[Level]Color{
 }
 
 var (
-	DefaultLogger    = NewLogger(procName())
-	DefaultLevel     = INFO
-	DefaultHandler   = StderrHandler
-	DefaultFormatter = &defaultFormatter{}
-	StdoutHandler    = NewWriterHandler(os.Stdout)
-	StderrHandler    = NewWriterHandler(os.Stderr)
+	DefaultLevel      = INFO
+	DefaultStackLevel = ERROR
+	DefaultHandler    = StderrHandler
+	DefaultFormatter  = &defaultFormatter{}
+	StdoutHandler     = NewWriterHandler(os.Stdout)
+	StderrHandler     = NewWriterHandler(os.Stderr)
+	DefaultLogger     = NewLogger(procName())
 )
 
-func init() {
-	StdoutHandler.Colorize = true
-	StderrHandler.Colorize = true
-}
-
 // Logger is the interface for outputing log messages in different levels.
 // A new Logger can be created with NewLogger() function.
 // You can changed the output handler with SetHandler() function.
@@ -81,6 +75,10 @@ type Logger interface {
 	// SetHandler replaces the current handler for output. Default is logging.StderrHandler.
 	SetHandler(Handler)
 
+	// SetStackLevel changes the level at and above which a stack trace is
+	// captured and attached to the Record. Default is logging.ERROR.
+	SetStackLevel(Level)
+
 	// Fatal is equivalent to l.Critical followed by a call to os.Exit(1).
 	Fatal(format string, args ...interface{})
 
@@ -104,8 +102,20 @@ type Logger interface {
 
 	// Debug logs a message using DEBUG as log level.
 	Debug(format string, args ...interface{})
+
+	// WithField returns a child Logger that attaches key/value to every
+	// record it logs, in addition to this logger's own fields.
+	WithField(key string, value interface{}) Logger
+
+	// WithFields returns a child Logger that attaches fields to every
+	// record it logs, in addition to this logger's own fields.
+	WithFields(fields Fields) Logger
 }
 
+// Fields is a set of contextual key/value pairs attached to a Logger via
+// WithField/WithFields and carried on every Record it produces.
+type Fields map[string]interface{}
+
 // Handler handles the output.
 type Handler interface {
 	SetFormatter(Formatter)
@@ -114,6 +124,10 @@ type Handler interface {
 	// Handle single log record.
 	Handle(*Record)
 
+	// Flush blocks until any records buffered by the handler have been
+	// written out. Handlers that do not buffer can treat this as a no-op.
+	Flush()
+
 	// Close the handler.
 	Close()
 }
@@ -129,6 +143,8 @@ type Record struct {
 	Line        int
 	ProcessID   int
 	ProcessName string
+	Fields      Fields
+	Stack       []runtime.Frame
 }
 
 // Formatter formats a record.
@@ -145,9 +161,21 @@ type Formatter interface {
 
 type defaultFormatter struct{}
 
-// Format outputs a message like "2014-02-28 18:15:57 [example] INFO     something happened"
+// Format outputs a message like "2014-02-28 18:15:57 [example] INFO     something happened",
+// followed by an indented stack trace when the Record carries one.
 func (f *defaultFormatter) Format(rec *Record) string {
-	return fmt.Sprintf("%s [%s] %-8s %s", fmt.Sprint(rec.Time)[:19], rec.LoggerName, LevelNames[rec.Level], fmt.Sprintf(rec.Format, rec.Args...))
+	message := fmt.Sprintf("%s [%s] %-8s %s", fmt.Sprint(rec.Time)[:19], rec.LoggerName, LevelNames[rec.Level], fmt.Sprintf(rec.Format, rec.Args...))
+	if len(rec.Stack) == 0 {
+		return message
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimSuffix(message, "\n"))
+	b.WriteByte('\n')
+	for _, frame := range rec.Stack {
+		fmt.Fprintf(&b, "\t%s\n\t\t%s:%d\n", frame.Function, frame.File, frame.Line)
+	}
+	return b.String()
 }
 
 ///////////////////////////
@@ -158,21 +186,25 @@ func (f *defaultFormatter) Format(rec *Record) string {
 
 // logger is the default Logger implementation.
 type logger struct {
-	Name    string
-	Level   Level
-	Handler Handler
+	Name       string
+	Level      Level
+	Handler    Handler
+	Fields     Fields
+	StackLevel Level
 }
 
 // NewLogger returns a new Logger implementation. Do not forget to close it at exit.
 func NewLogger(name string) Logger {
 	return &logger{
-		Name:    name,
-		Level:   DefaultLevel,
-		Handler: DefaultHandler,
+		Name:       name,
+		Level:      DefaultLevel,
+		Handler:    DefaultHandler,
+		StackLevel: DefaultStackLevel,
 	}
 }
 
 func (l *logger) Close() {
+	l.Handler.Flush()
 	l.Handler.Close()
 }
 
@@ -184,6 +216,31 @@ func (l *logger) SetHandler(b Handler) {
 	l.Handler = b
 }
 
+func (l *logger) WithField(key string, value interface{}) Logger {
+	return l.WithFields(Fields{key: value})
+}
+
+func (l *logger) WithFields(fields Fields) Logger {
+	merged := make(Fields, len(l.Fields)+len(fields))
+	for k, v := range l.Fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &logger{
+		Name:       l.Name,
+		Level:      l.Level,
+		Handler:    l.Handler,
+		Fields:     merged,
+		StackLevel: l.StackLevel,
+	}
+}
+
+func (l *logger) SetStackLevel(level Level) {
+	l.StackLevel = level
+}
+
 func (l *logger) Fatal(format string, args ...interface{}) {
 	l.Critical(format, args...)
 	l.Close()
@@ -191,7 +248,12 @@ func (l *logger) Fatal(format string, args ...interface{}) {
 }
 
 func (l *logger) Panic(format string, args ...interface{}) {
-	l.Critical(format, args...)
+	// Call log() directly rather than l.Critical so the captured file,
+	// line and stack trace point at the original Panic call site instead
+	// of this wrapper.
+	if l.Level >= CRITICAL {
+		l.log(CRITICAL, format, args...)
+	}
 	l.Close()
 	panic(fmt.Sprintf(format, args...))
 }
@@ -254,11 +316,38 @@ func (l *logger) log(level Level, format string, args ...interface{}) {
 		Line:        line,
 		ProcessName: procName(),
 		ProcessID:   os.Getpid(),
+		Fields:      l.Fields,
+	}
+
+	if level <= l.StackLevel {
+		rec.Stack = captureStack(4)
 	}
 
 	l.Handler.Handle(rec)
 }
 
+// captureStack returns the stack above the frame skip levels up (in the
+// same counting convention as runtime.Callers), trimmed of nothing below
+// that point so the first frame is the original logging call site.
+func captureStack(skip int) []runtime.Frame {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(skip, pcs)
+	if n == 0 {
+		return nil
+	}
+
+	frames := runtime.CallersFrames(pcs[:n])
+	stack := make([]runtime.Frame, 0, n)
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, frame)
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
 // procName returns the name of the current process.
 func procName() string { return filepath.Base(os.Args[0]) }
 
@@ -300,6 +389,14 @@ func Debug(format string, args ...interface{}) {
 	DefaultLogger.Debug(format, args...)
 }
 
+func WithField(key string, value interface{}) Logger {
+	return DefaultLogger.WithField(key, value)
+}
+
+func WithFields(fields Fields) Logger {
+	return DefaultLogger.WithFields(fields)
+}
+
 /////////////////
 //             //
 // BaseHandler //
@@ -326,6 +423,10 @@ func (h *BaseHandler) SetFormatter(f Formatter) {
 	h.Formatter = f
 }
 
+// Flush is a no-op by default; handlers that buffer records should
+// override it.
+func (h *BaseHandler) Flush() {}
+
 func (h *BaseHandler) FilterAndFormat(rec *Record) string {
 	if h.Level >= rec.Level {
 		return h.Formatter.Format(rec)
@@ -346,10 +447,17 @@ type WriterHandler struct {
 	Colorize bool
 }
 
+// NewWriterHandler returns a WriterHandler writing to w. Colorize defaults
+// to whether w looks like a terminal that can render ANSI color escapes:
+// it is off when the NO_COLOR environment variable is set, when w is not
+// an *os.File, when that file is not a TTY, or (on legacy Windows
+// consoles) when ENABLE_VIRTUAL_TERMINAL_PROCESSING could not be enabled.
+// Set Colorize explicitly to override the detected default.
 func NewWriterHandler(w io.Writer) *WriterHandler {
 	return &WriterHandler{
 		BaseHandler: NewBaseHandler(),
 		w:           w,
+		Colorize:    shouldColorize(w),
 	}
 }
 
@@ -369,66 +477,16 @@ func (b *WriterHandler) Handle(rec *Record) {
 
 func (b *WriterHandler) Close() {}
 
-///////////////////
-//               //
-// SyslogHandler //
-//               //
-///////////////////
-
-// SyslogHandler sends the logging output to syslog.
-type SyslogHandler struct {
-	*BaseHandler
-	w *syslog.Writer
-}
-
-func NewSyslogHandler(tag string) (*SyslogHandler, error) {
-	// Priority in New constructor is not important here because we
-	// do not use w.Write() directly.
-	w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_USER, tag)
-	if err != nil {
-		return nil, err
-	}
-	return &SyslogHandler{
-		BaseHandler: NewBaseHandler(),
-		w:           w,
-	}, nil
-}
-
-func (b *SyslogHandler) Handle(rec *Record) {
-	message := b.BaseHandler.FilterAndFormat(rec)
-	if message == "" {
-		return
-	}
-
-	var fn func(string) error
-	switch rec.Level {
-	case CRITICAL:
-		fn = b.w.Crit
-	case ERROR:
-		fn = b.w.Err
-	case WARNING:
-		fn = b.w.Warning
-	case NOTICE:
-		fn = b.w.Notice
-	case INFO:
-		fn = b.w.Info
-	case DEBUG:
-		fn = b.w.Debug
-	}
-	fn(message)
-}
-
-func (b *SyslogHandler) Close() {
-	b.w.Close()
-}
-
 //////////////////
 //              //
 // MultiHandler //
 //              //
 //////////////////
 
-// MultiHandler sends the log output to multiple handlers concurrently.
+// MultiHandler sends the log output to multiple handlers. Handle dispatches
+// to each child in turn on the caller's goroutine; wrap a slow child in an
+// AsyncHandler (or use NewAsyncMultiHandler) if it should not block the
+// others.
 type MultiHandler struct {
 	handlers []Handler
 }
@@ -437,6 +495,18 @@ func NewMultiHandler(handlers ...Handler) *MultiHandler {
 	return &MultiHandler{handlers: handlers}
 }
 
+// NewAsyncMultiHandler wraps each handler in an AsyncHandler with the given
+// capacity and overflow policy before fanning out to them, so a slow or
+// blocking child holds up the others and the caller only once its buffer
+// is full, and then only under the Block policy.
+func NewAsyncMultiHandler(capacity int, policy OverflowPolicy, handlers ...Handler) *MultiHandler {
+	wrapped := make([]Handler, len(handlers))
+	for i, h := range handlers {
+		wrapped[i] = NewAsyncHandler(h, capacity, policy)
+	}
+	return &MultiHandler{handlers: wrapped}
+}
+
 func (b *MultiHandler) SetFormatter(f Formatter) {
 	for _, h := range b.handlers {
 		h.SetFormatter(f)
@@ -450,25 +520,19 @@ func (b *MultiHandler) SetLevel(l Level) {
 }
 
 func (b *MultiHandler) Handle(rec *Record) {
-	wg := sync.WaitGroup{}
-	wg.Add(len(b.handlers))
 	for _, handler := range b.handlers {
-		go func(handler Handler) {
-			handler.Handle(rec)
-			wg.Done()
-		}(handler)
+		handler.Handle(rec)
+	}
+}
+
+func (b *MultiHandler) Flush() {
+	for _, handler := range b.handlers {
+		handler.Flush()
 	}
-	wg.Wait()
 }
 
 func (b *MultiHandler) Close() {
-	wg := sync.WaitGroup{}
-	wg.Add(len(b.handlers))
 	for _, handler := range b.handlers {
-		go func(handler Handler) {
-			handler.Close()
-			wg.Done()
-		}(handler)
+		handler.Close()
 	}
-	wg.Wait()
 }
@@ -0,0 +1,169 @@
+package logging
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+///////////////
+//           //
+// RepoLogger //
+//           //
+///////////////
+
+// RepoLogger is a registry of named Loggers that lets a caller set levels
+// for groups of loggers by glob pattern, similar to capnslog's
+// MustRepoLogger or geth's --vmodule flag. Loggers are created lazily and
+// cached by name, so turning on DEBUG for one subsystem does not require
+// drowning in output from the rest.
+type RepoLogger struct {
+	mu       sync.Mutex
+	loggers  map[string]*logger
+	patterns []vmodulePattern
+}
+
+type vmodulePattern struct {
+	pattern string
+	level   Level
+}
+
+// NewRepoLogger returns an empty RepoLogger.
+func NewRepoLogger() *RepoLogger {
+	return &RepoLogger{loggers: make(map[string]*logger)}
+}
+
+// DefaultRepoLogger is the registry used by the package-level GetLogger
+// and SetLevels helpers.
+var DefaultRepoLogger = NewRepoLogger()
+
+// GetLogger returns DefaultRepoLogger's Logger for pkg. See
+// RepoLogger.GetLogger.
+func GetLogger(pkg string) Logger {
+	return DefaultRepoLogger.GetLogger(pkg)
+}
+
+// SetLevels applies per-pattern levels on DefaultRepoLogger. See
+// RepoLogger.SetLevels.
+func SetLevels(levels map[string]Level) {
+	DefaultRepoLogger.SetLevels(levels)
+}
+
+// GetLogger returns the Logger registered for pkg, creating it (at
+// DefaultLevel unless a pattern passed to SetLevels already matches pkg,
+// using DefaultHandler) the first time pkg is requested.
+func (r *RepoLogger) GetLogger(pkg string) Logger {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if l, ok := r.loggers[pkg]; ok {
+		return l
+	}
+
+	l := &logger{
+		Name:       pkg,
+		Level:      r.levelForLocked(pkg),
+		Handler:    DefaultHandler,
+		StackLevel: DefaultStackLevel,
+	}
+	r.loggers[pkg] = l
+	return l
+}
+
+// SetLevels sets the level of every registered (and future) logger whose
+// name matches a pattern key, à la geth's --vmodule. In a pattern, "*"
+// matches any sequence of characters, including "/", so
+// "github.com/foo/*" also covers nested subpackages like
+// "github.com/foo/bar/baz"; a pattern with no "*" matches only that exact
+// name. When several patterns match the same logger, the longest (most
+// specific) pattern wins.
+func (r *RepoLogger) SetLevels(levels map[string]Level) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for pattern, level := range levels {
+		r.patterns = append(r.patterns, vmodulePattern{pattern: pattern, level: level})
+	}
+	for name, l := range r.loggers {
+		l.Level = r.levelForLocked(name)
+	}
+}
+
+// levelForLocked returns the level that should apply to name given the
+// patterns registered so far. Caller must hold r.mu.
+func (r *RepoLogger) levelForLocked(name string) Level {
+	level := DefaultLevel
+	best := -1
+	for _, p := range r.patterns {
+		if !vmoduleMatch(p.pattern, name) {
+			continue
+		}
+		// ">=" lets a pattern set by a later SetLevels call replace the
+		// level of an equally long one registered earlier.
+		if specificity := len(p.pattern); specificity >= best {
+			best = specificity
+			level = p.level
+		}
+	}
+	return level
+}
+
+func vmoduleMatch(pattern, name string) bool {
+	if pattern == name {
+		return true
+	}
+	if !strings.Contains(pattern, "*") {
+		return false
+	}
+	re, err := vmoduleRegexp(pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(name)
+}
+
+// vmoduleRegexp compiles a vmodule glob pattern into a regexp where "*"
+// matches any sequence of characters (unlike path.Match's "*", which
+// cannot cross a "/"), so a pattern can match an entire package subtree.
+func vmoduleRegexp(pattern string) (*regexp.Regexp, error) {
+	parts := strings.Split(pattern, "*")
+	quoted := make([]string, len(parts))
+	for i, part := range parts {
+		quoted[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.Compile("^" + strings.Join(quoted, ".*") + "$")
+}
+
+// ParseVmodule parses a vmodule-style string such as
+// "github.com/foo/*=DEBUG,main=INFO" into the map expected by SetLevels.
+func ParseVmodule(vmodule string) (map[string]Level, error) {
+	levels := make(map[string]Level)
+	if vmodule == "" {
+		return levels, nil
+	}
+
+	levelByName := make(map[string]Level, len(LevelNames))
+	for level, name := range LevelNames {
+		levelByName[name] = level
+	}
+
+	for _, entry := range strings.Split(vmodule, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("logging: invalid vmodule entry %q", entry)
+		}
+		pattern := strings.TrimSpace(parts[0])
+		levelName := strings.ToUpper(strings.TrimSpace(parts[1]))
+		level, ok := levelByName[levelName]
+		if !ok {
+			return nil, fmt.Errorf("logging: unknown level %q in vmodule entry %q", parts[1], entry)
+		}
+		levels[pattern] = level
+	}
+	return levels, nil
+}
@@ -0,0 +1,46 @@
+package logging
+
+import "testing"
+
+func TestVmoduleMatchCrossesPathSegments(t *testing.T) {
+	cases := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"github.com/foo/*", "github.com/foo/bar", true},
+		{"github.com/foo/*", "github.com/foo/bar/baz", true},
+		{"github.com/foo/*", "github.com/other/bar", false},
+		{"main", "main", true},
+		{"main", "other", false},
+	}
+
+	for _, c := range cases {
+		if got := vmoduleMatch(c.pattern, c.name); got != c.want {
+			t.Errorf("vmoduleMatch(%q, %q) = %v, want %v", c.pattern, c.name, got, c.want)
+		}
+	}
+}
+
+func TestRepoLoggerSetLevelsMostSpecificWins(t *testing.T) {
+	r := NewRepoLogger()
+	r.SetLevels(map[string]Level{
+		"github.com/foo/*":     DEBUG,
+		"github.com/foo/quiet": WARNING,
+	})
+
+	if l := r.GetLogger("github.com/foo/bar"); l.(*logger).Level != DEBUG {
+		t.Fatalf("expected github.com/foo/bar to get DEBUG, got %v", l.(*logger).Level)
+	}
+	if l := r.GetLogger("github.com/foo/quiet"); l.(*logger).Level != WARNING {
+		t.Fatalf("expected the more specific pattern to win, got %v", l.(*logger).Level)
+	}
+}
+
+func TestRepoLoggerGetLoggerDefaultsStackLevel(t *testing.T) {
+	r := NewRepoLogger()
+	l := r.GetLogger("github.com/foo/bar").(*logger)
+	if l.StackLevel != DefaultStackLevel {
+		t.Fatalf("expected StackLevel %v, got %v", DefaultStackLevel, l.StackLevel)
+	}
+}
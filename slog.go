@@ -0,0 +1,144 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"runtime"
+)
+
+// levelToSlog maps a Level to the nearest log/slog level.
+func levelToSlog(l Level) slog.Level {
+	switch l {
+	case CRITICAL, ERROR:
+		return slog.LevelError
+	case WARNING:
+		return slog.LevelWarn
+	case NOTICE, INFO:
+		return slog.LevelInfo
+	default: // DEBUG
+		return slog.LevelDebug
+	}
+}
+
+// levelFromSlog maps a log/slog level to the nearest Level.
+func levelFromSlog(l slog.Level) Level {
+	switch {
+	case l >= slog.LevelError:
+		return ERROR
+	case l >= slog.LevelWarn:
+		return WARNING
+	case l >= slog.LevelInfo:
+		return INFO
+	default:
+		return DEBUG
+	}
+}
+
+/////////////////
+//             //
+// SlogHandler //
+//             //
+/////////////////
+
+// SlogHandler adapts a Handler from this package to the log/slog.Handler
+// interface, so a Logger's output can be consumed through slog.New or
+// slog.SetDefault.
+type SlogHandler struct {
+	handler Handler
+	name    string
+	prefix  string
+	fields  Fields
+}
+
+// NewSlogHandler returns a slog.Handler that forwards every record to
+// handler, tagging it with loggerName.
+func NewSlogHandler(loggerName string, handler Handler) *SlogHandler {
+	return &SlogHandler{handler: handler, name: loggerName}
+}
+
+func (h *SlogHandler) Enabled(_ context.Context, _ slog.Level) bool {
+	return true
+}
+
+func (h *SlogHandler) Handle(_ context.Context, r slog.Record) error {
+	fields := make(Fields, len(h.fields)+r.NumAttrs())
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		fields[h.prefix+a.Key] = a.Value.Any()
+		return true
+	})
+
+	rec := &Record{
+		Format:      "%s",
+		Args:        []interface{}{r.Message},
+		LoggerName:  h.name,
+		Level:       levelFromSlog(r.Level),
+		Time:        r.Time,
+		ProcessName: procName(),
+		ProcessID:   os.Getpid(),
+		Fields:      fields,
+	}
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		rec.Filename = frame.File
+		rec.Line = frame.Line
+	}
+
+	h.handler.Handle(rec)
+	return nil
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make(Fields, len(h.fields)+len(attrs))
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	for _, a := range attrs {
+		fields[h.prefix+a.Key] = a.Value.Any()
+	}
+	return &SlogHandler{handler: h.handler, name: h.name, prefix: h.prefix, fields: fields}
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &SlogHandler{handler: h.handler, name: h.name, prefix: h.prefix + name + ".", fields: h.fields}
+}
+
+//////////////////
+//              //
+// FromSlog     //
+//              //
+//////////////////
+
+// fromSlog adapts a log/slog.Handler so it can be used as a Handler,
+// letting any slog-compatible sink (OpenTelemetry, cloud logging
+// exporters, ...) receive this package's records.
+type fromSlog struct {
+	*BaseHandler
+	slog slog.Handler
+}
+
+// FromSlog wraps a log/slog.Handler so it can be passed to Logger.SetHandler.
+func FromSlog(h slog.Handler) Handler {
+	return &fromSlog{BaseHandler: NewBaseHandler(), slog: h}
+}
+
+func (h *fromSlog) Handle(rec *Record) {
+	if h.Level < rec.Level {
+		return
+	}
+
+	sr := slog.NewRecord(rec.Time, levelToSlog(rec.Level), recordMessage(rec), 0)
+	sr.AddAttrs(slog.String("logger", rec.LoggerName))
+	for k, v := range rec.Fields {
+		sr.AddAttrs(slog.Any(k, v))
+	}
+	h.slog.Handle(context.Background(), sr)
+}
+
+func (h *fromSlog) Close() {}
@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestSlogHandlerForwardsToHandler(t *testing.T) {
+	sink := newCountingHandler(0)
+	h := NewSlogHandler("app", sink)
+
+	r := slog.NewRecord(time.Now(), slog.LevelWarn, "something happened", 0)
+	r.AddAttrs(slog.String("key", "value"))
+
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle returned error: %s", err)
+	}
+	if sink.Counted() != 1 {
+		t.Fatalf("expected the wrapped handler to receive 1 record, got %d", sink.Counted())
+	}
+}
+
+func TestLevelMapping(t *testing.T) {
+	cases := []struct {
+		level Level
+		want  slog.Level
+	}{
+		{CRITICAL, slog.LevelError},
+		{ERROR, slog.LevelError},
+		{WARNING, slog.LevelWarn},
+		{NOTICE, slog.LevelInfo},
+		{INFO, slog.LevelInfo},
+		{DEBUG, slog.LevelDebug},
+	}
+	for _, c := range cases {
+		if got := levelToSlog(c.level); got != c.want {
+			t.Errorf("levelToSlog(%v) = %v, want %v", c.level, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+//go:build windows
+
+package logging
+
+import "errors"
+
+///////////////////
+//               //
+// SyslogHandler //
+//               //
+///////////////////
+
+// SyslogHandler sends the logging output to syslog. Syslog does not exist on
+// Windows, so NewSyslogHandler always fails here; use a different Handler
+// (e.g. WriterHandler or FileHandler) instead.
+type SyslogHandler struct {
+	*BaseHandler
+}
+
+func NewSyslogHandler(tag string) (*SyslogHandler, error) {
+	return nil, errors.New("logging: SyslogHandler is not supported on windows")
+}
+
+func (b *SyslogHandler) Handle(rec *Record) {}
+
+func (b *SyslogHandler) Close() {}